@@ -0,0 +1,13 @@
+package model
+
+///////////////////////////////////////////////////////////////////////////////
+// BlobRef
+
+// BlobRef refers to a blob stored outside the document, e.g. via a
+// view.BlobStorage, instead of holding the data inline like File or Blob do.
+type BlobRef struct {
+	Ref         string
+	Name        string
+	ContentType string
+	Size        int64
+}
@@ -0,0 +1,119 @@
+package view
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ungerik/go-start/model"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// BlobStorage
+
+// BlobStorage stores uploaded file data out of band from the model document that references it.
+type BlobStorage interface {
+	// Store reads r until EOF and returns a ref that can later be passed to
+	// Open or Delete, together with the number of bytes written.
+	Store(ctx *Context, name, contentType string, r io.Reader) (ref string, size int64, err error)
+
+	// Open returns a reader for the blob identified by ref.
+	Open(ref string) (io.ReadCloser, error)
+
+	// Delete removes the blob identified by ref.
+	Delete(ref string) error
+}
+
+// DefaultBlobStorage is the package wide BlobStorage that Form.BlobStorage returns.
+// A Form has no BlobStorage setting of its own.
+var DefaultBlobStorage BlobStorage
+
+///////////////////////////////////////////////////////////////////////////////
+// FilesystemBlobStorage
+
+// FilesystemBlobStorage is a BlobStorage that stores blobs as files below
+// Dir, named after the ref returned from Store.
+type FilesystemBlobStorage struct {
+	Dir string
+}
+
+func NewFilesystemBlobStorage(dir string) *FilesystemBlobStorage {
+	return &FilesystemBlobStorage{Dir: dir}
+}
+
+func (self *FilesystemBlobStorage) Store(ctx *Context, name, contentType string, r io.Reader) (ref string, size int64, err error) {
+	ref = model.NewUUID().String()
+	file, err := os.OpenFile(filepath.Join(self.Dir, ref), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+	size, err = io.Copy(file, r)
+	if err != nil {
+		os.Remove(filepath.Join(self.Dir, ref))
+		return "", 0, err
+	}
+	return ref, size, nil
+}
+
+func (self *FilesystemBlobStorage) Open(ref string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(self.Dir, ref))
+}
+
+func (self *FilesystemBlobStorage) Delete(ref string) error {
+	return os.Remove(filepath.Join(self.Dir, ref))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// S3BlobStorage
+
+// S3Client is the minimal subset of an S3-compatible object store that S3BlobStorage needs.
+type S3Client interface {
+	PutObject(bucket, key, contentType string, r io.Reader) error
+	GetObject(bucket, key string) (io.ReadCloser, error)
+	DeleteObject(bucket, key string) error
+}
+
+// S3BlobStorage is a BlobStorage backed by an S3-compatible object store.
+type S3BlobStorage struct {
+	Client S3Client
+	Bucket string
+	Prefix string
+}
+
+func NewS3BlobStorage(client S3Client, bucket, prefix string) *S3BlobStorage {
+	return &S3BlobStorage{Client: client, Bucket: bucket, Prefix: prefix}
+}
+
+func (self *S3BlobStorage) Store(ctx *Context, name, contentType string, r io.Reader) (ref string, size int64, err error) {
+	ref = self.Prefix + model.NewUUID().String()
+	counting := &countingReader{r: r}
+	err = self.Client.PutObject(self.Bucket, ref, contentType, counting)
+	if err != nil {
+		return "", 0, err
+	}
+	return ref, counting.n, nil
+}
+
+func (self *S3BlobStorage) Open(ref string) (io.ReadCloser, error) {
+	return self.Client.GetObject(self.Bucket, ref)
+}
+
+func (self *S3BlobStorage) Delete(ref string) error {
+	return self.Client.DeleteObject(self.Bucket, ref)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// countingReader
+
+// countingReader wraps an io.Reader and counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (self *countingReader) Read(p []byte) (n int, err error) {
+	n, err = self.r.Read(p)
+	self.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,84 @@
+package view
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// CSRFFieldName is the name of the hidden form field and POST parameter that
+// carries the CSRF synchronizer token.
+const CSRFFieldName = "gostart_csrf"
+
+// CSRFSecret HMACs CSRF tokens and must be set from Config before use.
+var CSRFSecret []byte
+
+///////////////////////////////////////////////////////////////////////////////
+// ErrCSRFTokenInvalid
+
+// ErrCSRFTokenInvalid is returned by VerifyCSRFToken on a missing or mismatching token.
+type ErrCSRFTokenInvalid struct{}
+
+func (self ErrCSRFTokenInvalid) Error() string {
+	return "Invalid or missing CSRF token"
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// CSRFToken
+
+// CSRFToken returns the CSRF synchronizer token for the session of ctx.
+func (ctx *Context) CSRFToken() string {
+	return signCSRFToken(ctx.Session().ID())
+}
+
+// VerifyCSRFToken checks the CSRFFieldName value of the request against
+// ctx.CSRFToken() using a constant time comparison.
+func VerifyCSRFToken(ctx *Context) error {
+	submitted := ctx.Request.FormValue(CSRFFieldName)
+	expected := ctx.CSRFToken()
+	if submitted == "" || subtle.ConstantTimeCompare([]byte(submitted), []byte(expected)) != 1 {
+		return ErrCSRFTokenInvalid{}
+	}
+	return nil
+}
+
+// MustVerifyCSRFToken panics with ErrCSRFTokenInvalid if the request does
+// not carry a valid CSRF token, for non-form POST handlers such as AJAX endpoints.
+func (ctx *Context) MustVerifyCSRFToken() {
+	if err := VerifyCSRFToken(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func signCSRFToken(sessionID string) string {
+	if len(CSRFSecret) == 0 {
+		panic("view.CSRFSecret is not set")
+	}
+	mac := hmac.New(sha256.New, CSRFSecret)
+	mac.Write([]byte(sessionID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// CSRFField
+
+// CSRFField is the hidden input that carries the CSRF synchronizer token.
+type CSRFField struct {
+	ViewBaseWithId
+	Value string
+}
+
+func (self *CSRFField) Render(response *Response) (err error) {
+	response.XML.OpenTag("input")
+	response.XML.Attrib("type", "hidden")
+	response.XML.Attrib("name", CSRFFieldName)
+	response.XML.Attrib("value", self.Value)
+	response.XML.ForceCloseTag()
+	return nil
+}
+
+// NewCSRFField creates the hidden CSRF input for the session of ctx.
+func NewCSRFField(ctx *Context) *CSRFField {
+	return &CSRFField{Value: ctx.CSRFToken()}
+}
@@ -0,0 +1,68 @@
+package view
+
+///////////////////////////////////////////////////////////////////////////////
+// DateField
+
+// DateField represents a HTML5 date input element that lets browsers offer
+// a native date picker instead of a plain text field.
+// Value must be formatted as "2006-01-02" as required by the HTML5 spec.
+type DateField struct {
+	ViewBaseWithId
+	Class       string
+	Name        string
+	Value       string
+	Min         string
+	Max         string
+	Disabled    bool
+	Placeholder string
+}
+
+func (self *DateField) Render(response *Response) (err error) {
+	response.XML.OpenTag("input")
+	response.XML.Attrib("type", "date")
+	response.XML.Attrib("id", self.id)
+	response.XML.Attrib("name", self.Name)
+	response.XML.AttribIfNotDefault("class", self.Class)
+	response.XML.AttribIfNotDefault("value", self.Value)
+	response.XML.AttribIfNotDefault("min", self.Min)
+	response.XML.AttribIfNotDefault("max", self.Max)
+	response.XML.AttribIfNotDefault("placeholder", self.Placeholder)
+	if self.Disabled {
+		response.XML.Attrib("disabled", "disabled")
+	}
+	response.XML.ForceCloseTag()
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DateTimeField
+
+// DateTimeField represents a HTML5 datetime-local input element.
+// Value must be formatted as "2006-01-02T15:04" as required by the HTML5 spec.
+type DateTimeField struct {
+	ViewBaseWithId
+	Class       string
+	Name        string
+	Value       string
+	Min         string
+	Max         string
+	Disabled    bool
+	Placeholder string
+}
+
+func (self *DateTimeField) Render(response *Response) (err error) {
+	response.XML.OpenTag("input")
+	response.XML.Attrib("type", "datetime-local")
+	response.XML.Attrib("id", self.id)
+	response.XML.Attrib("name", self.Name)
+	response.XML.AttribIfNotDefault("class", self.Class)
+	response.XML.AttribIfNotDefault("value", self.Value)
+	response.XML.AttribIfNotDefault("min", self.Min)
+	response.XML.AttribIfNotDefault("max", self.Max)
+	response.XML.AttribIfNotDefault("placeholder", self.Placeholder)
+	if self.Disabled {
+		response.XML.Attrib("disabled", "disabled")
+	}
+	response.XML.ForceCloseTag()
+	return nil
+}
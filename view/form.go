@@ -0,0 +1,113 @@
+package view
+
+import (
+	"github.com/ungerik/go-start/model"
+)
+
+// Form renders a HTML form for Fields and dispatches submitted values back
+// into them through FieldControllers.
+type Form struct {
+	ViewBaseWithId
+	Action           string
+	Fields           []*model.MetaData
+	FieldControllers FormFieldControllers
+	DisableCSRF      bool
+	HTMXValidation   bool
+	LiveValidate     bool
+	disabledFields   map[string]bool
+	placeholders     map[string]string
+}
+
+// NewForm creates a Form posting to action with the StandardFormFieldControllers.
+func NewForm(action string, fields []*model.MetaData) *Form {
+	return &Form{Action: action, Fields: fields, FieldControllers: StandardFormFieldControllers}
+}
+
+func (self *Form) BlobStorage() BlobStorage {
+	return DefaultBlobStorage
+}
+
+func (self *Form) FieldInputClass(metaData *model.MetaData) string {
+	return ""
+}
+
+func (self *Form) FieldLabel(metaData *model.MetaData) string {
+	return metaData.Selector()
+}
+
+func (self *Form) GetInputSize(metaData *model.MetaData) int {
+	return 0
+}
+
+func (self *Form) IsFieldDisabled(metaData *model.MetaData) bool {
+	return self.disabledFields[metaData.Selector()]
+}
+
+func (self *Form) InputFieldPlaceholder(metaData *model.MetaData) string {
+	return self.placeholders[metaData.Selector()]
+}
+
+// Render writes the form tag, every field's input and, unless DisableCSRF
+// is set, a hidden CSRFField carrying the synchronizer token for ctx.
+func (self *Form) Render(ctx *Context, response *Response) (err error) {
+	response.XML.OpenTag("form").Attrib("action", self.Action).Attrib("method", "post")
+	for _, field := range self.Fields {
+		input, err := self.FieldControllers.NewInput(true, field, self)
+		if err != nil {
+			return err
+		}
+		if err = input.Render(response); err != nil {
+			return err
+		}
+	}
+	if !self.DisableCSRF {
+		if err = NewCSRFField(ctx).Render(response); err != nil {
+			return err
+		}
+	}
+	response.XML.ForceCloseTag()
+	return nil
+}
+
+// Submit verifies the CSRF token unless DisableCSRF is set, then dispatches
+// the submitted values of every field through FieldControllers.SetValue. If
+// fields fail to parse and the request is a HTMX request with HTMXValidation
+// enabled, it responds with a partial re-render of the failed fields instead
+// of returning ErrFormValidation.
+func (self *Form) Submit(ctx *Context, response *Response) error {
+	if !self.DisableCSRF {
+		if err := VerifyCSRFToken(ctx); err != nil {
+			return err
+		}
+	}
+	var fieldErrors []FieldError
+	for _, field := range self.Fields {
+		if err := self.FieldControllers.SetValue(ctx, field, self); err != nil {
+			input, inputErr := self.FieldControllers.NewInput(true, field, self)
+			if inputErr != nil {
+				return inputErr
+			}
+			container, _ := input.(*FieldContainer)
+			fieldErrors = append(fieldErrors, FieldError{Container: container, Message: err.Error()})
+		}
+	}
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+	if self.HTMXValidation && ctx.IsHTMXRequest() {
+		return RenderHTMXFieldErrors(ctx, response, fieldErrors)
+	}
+	return ErrFormValidation{FieldErrors: fieldErrors}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// ErrFormValidation
+
+// ErrFormValidation is returned by Submit when one or more fields failed to parse.
+type ErrFormValidation struct {
+	FieldErrors []FieldError
+}
+
+func (self ErrFormValidation) Error() string {
+	return "Form validation failed"
+}
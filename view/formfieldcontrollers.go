@@ -2,12 +2,20 @@ package view
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"strconv"
+	"time"
 
 	"github.com/ungerik/go-start/model"
 )
 
+const (
+	htmlDateLayout     = "2006-01-02"
+	htmlDateTimeLayout = "2006-01-02T15:04"
+)
+
 ///////////////////////////////////////////////////////////////////////////////
 // FormFieldController
 
@@ -53,7 +61,14 @@ func (self FormFieldControllers) Supports(metaData *model.MetaData, form *Form)
 func (self FormFieldControllers) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
 	for _, c := range self {
 		if c.Supports(metaData, form) {
-			return c.NewInput(withLabel, metaData, form)
+			input, err = c.NewInput(withLabel, metaData, form)
+			if err != nil {
+				return nil, err
+			}
+			if form.HTMXValidation {
+				input = NewFieldContainer(input, metaData, form)
+			}
+			return input, nil
 		}
 	}
 	return nil, ErrFormFieldTypeNotSupported{metaData}
@@ -75,10 +90,27 @@ type modelValueControllerBase struct{}
 
 func (self modelValueControllerBase) SetValue(ctx *Context, metaData *model.MetaData, form *Form) error {
 	value := metaData.Value.Addr().Interface().(model.Value)
-	value.SetString(ctx.Request.FormValue(metaData.Selector()))
+	str := ctx.Request.FormValue(metaData.Selector())
+	if str == "" {
+		if _, ok := metaData.Attrib(StructTagKey, "required"); ok {
+			return ErrFieldRequired{metaData}
+		}
+	}
+	value.SetString(str)
 	return nil
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// ErrFieldRequired
+
+type ErrFieldRequired struct {
+	*model.MetaData
+}
+
+func (self ErrFieldRequired) Error() string {
+	return fmt.Sprintf("Form field %s is required", self.Selector())
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // ModelStringController
 
@@ -170,10 +202,10 @@ func (self ModelUrlController) Supports(metaData *model.MetaData, form *Form) bo
 
 func (self ModelUrlController) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
 	url := metaData.Value.Addr().Interface().(*model.Url)
-	input = &TextField{
+	input = &UrlField{
 		Class:       form.FieldInputClass(metaData),
 		Name:        metaData.Selector(),
-		Text:        url.Get(),
+		Value:       url.Get(),
 		Size:        form.GetInputSize(metaData),
 		Disabled:    form.IsFieldDisabled(metaData),
 		Placeholder: form.InputFieldPlaceholder(metaData),
@@ -262,14 +294,18 @@ func (self ModelPhoneController) Supports(metaData *model.MetaData, form *Form)
 
 func (self ModelPhoneController) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
 	phone := metaData.Value.Addr().Interface().(*model.Phone)
-	input = &TextField{
+	telField := &TelField{
 		Class:       form.FieldInputClass(metaData),
 		Name:        metaData.Selector(),
-		Text:        phone.Get(),
+		Value:       phone.Get(),
 		Size:        form.GetInputSize(metaData),
 		Disabled:    form.IsFieldDisabled(metaData),
 		Placeholder: form.InputFieldPlaceholder(metaData),
 	}
+	if pattern, ok := metaData.Attrib(StructTagKey, "pattern"); ok {
+		telField.Pattern = pattern
+	}
+	input = telField
 	if withLabel {
 		return AddStandardLabel(form, input, metaData), nil
 	}
@@ -426,23 +462,45 @@ func (self ModelDateController) Supports(metaData *model.MetaData, form *Form) b
 
 func (self ModelDateController) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
 	date := metaData.Value.Addr().Interface().(*model.Date)
-	input = Views{
-		HTML("(Format: " + model.DateFormat + ")<br/>"),
-		&TextField{
-			Class:       form.FieldInputClass(metaData),
-			Name:        metaData.Selector(),
-			Text:        date.Get(),
-			Size:        len(model.DateFormat),
-			Disabled:    form.IsFieldDisabled(metaData),
-			Placeholder: form.InputFieldPlaceholder(metaData),
-		},
+	value := date.Get()
+	if t, err := time.Parse(model.DateFormat, value); err == nil {
+		value = t.Format(htmlDateLayout)
+	}
+	dateField := &DateField{
+		Class:       form.FieldInputClass(metaData),
+		Name:        metaData.Selector(),
+		Value:       value,
+		Disabled:    form.IsFieldDisabled(metaData),
+		Placeholder: form.InputFieldPlaceholder(metaData),
+	}
+	if str, ok := metaData.Attrib(StructTagKey, "min"); ok {
+		dateField.Min = htmlDateAttrib(metaData, "min", str, model.DateFormat, htmlDateLayout)
+	}
+	if str, ok := metaData.Attrib(StructTagKey, "max"); ok {
+		dateField.Max = htmlDateAttrib(metaData, "max", str, model.DateFormat, htmlDateLayout)
 	}
+	input = dateField
 	if withLabel {
 		return AddStandardLabel(form, input, metaData), nil
 	}
 	return input, nil
 }
 
+func (self ModelDateController) SetValue(ctx *Context, metaData *model.MetaData, form *Form) error {
+	date := metaData.Value.Addr().Interface().(*model.Date)
+	value := ctx.Request.FormValue(metaData.Selector())
+	if value == "" {
+		date.SetString("")
+		return nil
+	}
+	t, err := time.Parse(htmlDateLayout, value)
+	if err != nil {
+		return err
+	}
+	date.SetString(t.Format(model.DateFormat))
+	return nil
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // ModelDateTimeController
 
@@ -457,23 +515,45 @@ func (self ModelDateTimeController) Supports(metaData *model.MetaData, form *For
 
 func (self ModelDateTimeController) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
 	dateTime := metaData.Value.Addr().Interface().(*model.DateTime)
-	input = Views{
-		HTML("(Format: " + model.DateTimeFormat + ")<br/>"),
-		&TextField{
-			Class:       form.FieldInputClass(metaData),
-			Name:        metaData.Selector(),
-			Text:        dateTime.Get(),
-			Size:        len(model.DateTimeFormat),
-			Disabled:    form.IsFieldDisabled(metaData),
-			Placeholder: form.InputFieldPlaceholder(metaData),
-		},
+	value := dateTime.Get()
+	if t, err := time.Parse(model.DateTimeFormat, value); err == nil {
+		value = t.Format(htmlDateTimeLayout)
+	}
+	dateTimeField := &DateTimeField{
+		Class:       form.FieldInputClass(metaData),
+		Name:        metaData.Selector(),
+		Value:       value,
+		Disabled:    form.IsFieldDisabled(metaData),
+		Placeholder: form.InputFieldPlaceholder(metaData),
+	}
+	if str, ok := metaData.Attrib(StructTagKey, "min"); ok {
+		dateTimeField.Min = htmlDateAttrib(metaData, "min", str, model.DateTimeFormat, htmlDateTimeLayout)
+	}
+	if str, ok := metaData.Attrib(StructTagKey, "max"); ok {
+		dateTimeField.Max = htmlDateAttrib(metaData, "max", str, model.DateTimeFormat, htmlDateTimeLayout)
 	}
+	input = dateTimeField
 	if withLabel {
 		return AddStandardLabel(form, input, metaData), nil
 	}
 	return input, nil
 }
 
+func (self ModelDateTimeController) SetValue(ctx *Context, metaData *model.MetaData, form *Form) error {
+	dateTime := metaData.Value.Addr().Interface().(*model.DateTime)
+	value := ctx.Request.FormValue(metaData.Selector())
+	if value == "" {
+		dateTime.SetString("")
+		return nil
+	}
+	t, err := time.Parse(htmlDateTimeLayout, value)
+	if err != nil {
+		return err
+	}
+	dateTime.SetString(t.Format(model.DateTimeFormat))
+	return nil
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // ModelFloatController
 
@@ -488,13 +568,33 @@ func (self ModelFloatController) Supports(metaData *model.MetaData, form *Form)
 
 func (self ModelFloatController) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
 	f := metaData.Value.Addr().Interface().(*model.Float)
-	input = &TextField{
+	numberField := &NumberField{
 		Class:       form.FieldInputClass(metaData),
 		Name:        metaData.Selector(),
-		Text:        f.String(),
+		Value:       f.String(),
+		Step:        "any",
 		Disabled:    form.IsFieldDisabled(metaData),
 		Placeholder: form.InputFieldPlaceholder(metaData),
 	}
+	if str, ok := metaData.Attrib(StructTagKey, "min"); ok {
+		if _, err = strconv.ParseFloat(str, 64); err != nil {
+			panic("Error in ModelFloatController.NewInput(): " + err.Error())
+		}
+		numberField.Min = str
+	}
+	if str, ok := metaData.Attrib(StructTagKey, "max"); ok {
+		if _, err = strconv.ParseFloat(str, 64); err != nil {
+			panic("Error in ModelFloatController.NewInput(): " + err.Error())
+		}
+		numberField.Max = str
+	}
+	if str, ok := metaData.Attrib(StructTagKey, "step"); ok {
+		if _, err = strconv.ParseFloat(str, 64); err != nil {
+			panic("Error in ModelFloatController.NewInput(): " + err.Error())
+		}
+		numberField.Step = str
+	}
+	input = numberField
 	if withLabel {
 		return AddStandardLabel(form, input, metaData), nil
 	}
@@ -515,19 +615,100 @@ func (self ModelIntController) Supports(metaData *model.MetaData, form *Form) bo
 
 func (self ModelIntController) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
 	i := metaData.Value.Addr().Interface().(*model.Int)
-	input = &TextField{
+	numberField := &NumberField{
 		Class:       form.FieldInputClass(metaData),
 		Name:        metaData.Selector(),
-		Text:        i.String(),
+		Value:       i.String(),
+		Step:        "1",
 		Disabled:    form.IsFieldDisabled(metaData),
 		Placeholder: form.InputFieldPlaceholder(metaData),
 	}
+	if str, ok := metaData.Attrib(StructTagKey, "min"); ok {
+		if _, err = strconv.Atoi(str); err != nil {
+			panic("Error in ModelIntController.NewInput(): " + err.Error())
+		}
+		numberField.Min = str
+	}
+	if str, ok := metaData.Attrib(StructTagKey, "max"); ok {
+		if _, err = strconv.Atoi(str); err != nil {
+			panic("Error in ModelIntController.NewInput(): " + err.Error())
+		}
+		numberField.Max = str
+	}
+	if str, ok := metaData.Attrib(StructTagKey, "step"); ok {
+		if _, err = strconv.Atoi(str); err != nil {
+			panic("Error in ModelIntController.NewInput(): " + err.Error())
+		}
+		numberField.Step = str
+	}
+	input = numberField
 	if withLabel {
 		return AddStandardLabel(form, input, metaData), nil
 	}
 	return input, nil
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// htmlDateAttrib
+
+// htmlDateAttrib parses a min/max struct tag value in modelLayout and
+// reformats it to htmlLayout for use as a DateField/DateTimeField attribute.
+func htmlDateAttrib(metaData *model.MetaData, tagName, str, modelLayout, htmlLayout string) string {
+	t, err := time.Parse(modelLayout, str)
+	if err != nil {
+		panic("Error in " + metaData.Selector() + " " + tagName + " tag: " + err.Error())
+	}
+	return t.Format(htmlLayout)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// maxUploadBytes
+
+// maxUploadBytes reads the optional "maxuploadbytes" attribute of the StructTagKey struct tag.
+func maxUploadBytes(metaData *model.MetaData) (n int64, ok bool) {
+	str, ok := metaData.Attrib(StructTagKey, "maxuploadbytes")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		panic("Error in maxUploadBytes(): " + err.Error())
+	}
+	return n, true
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// storeThenLoadSmall
+
+// storeThenLoadSmall streams file through storage so an upload never has to
+// fit in memory as a whole, then reads it back as an inline fallback only if
+// it stayed within max. An upload larger than max is deleted from storage and
+// ErrUploadTooLarge is returned. Without a max nothing is read back: the
+// upload stays in storage and nil data is returned.
+func storeThenLoadSmall(ctx *Context, storage BlobStorage, header *multipart.FileHeader, file multipart.File, metaData *model.MetaData, max int64, hasMax bool) ([]byte, error) {
+	var reader io.Reader = file
+	if hasMax {
+		reader = io.LimitReader(file, max+1)
+	}
+	ref, size, err := storage.Store(ctx, header.Filename, header.Header.Get("Content-Type"), reader)
+	if err != nil {
+		return nil, err
+	}
+	if hasMax && size > max {
+		storage.Delete(ref)
+		return nil, ErrUploadTooLarge{MetaData: metaData, MaxUploadBytes: max}
+	}
+	if !hasMax {
+		return nil, nil
+	}
+	blob, err := storage.Open(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+	return ioutil.ReadAll(blob)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // ModelFileController
 
@@ -557,10 +738,27 @@ func (self ModelFileController) SetValue(ctx *Context, metaData *model.MetaData,
 		return err
 	}
 	defer file.Close()
-	bytes, err := ioutil.ReadAll(file)
+	max, hasMax := maxUploadBytes(metaData)
+	if storage := form.BlobStorage(); storage != nil {
+		bytes, err := storeThenLoadSmall(ctx, storage, header, file, metaData, max, hasMax)
+		if err != nil {
+			return err
+		}
+		f.Name = header.Filename
+		f.Data = bytes
+		return nil
+	}
+	var reader io.Reader = file
+	if hasMax {
+		reader = io.LimitReader(file, max+1)
+	}
+	bytes, err := ioutil.ReadAll(reader)
 	if err != nil {
 		return err
 	}
+	if hasMax && int64(len(bytes)) > max {
+		return ErrUploadTooLarge{MetaData: metaData, MaxUploadBytes: max}
+	}
 	f.Name = header.Filename
 	f.Data = bytes
 	return nil
@@ -590,15 +788,140 @@ func (self ModelBlobController) NewInput(withLabel bool, metaData *model.MetaDat
 
 func (self ModelBlobController) SetValue(ctx *Context, metaData *model.MetaData, form *Form) error {
 	b := metaData.Value.Addr().Interface().(*model.Blob)
-	file, _, err := ctx.Request.FormFile(metaData.Selector())
+	file, header, err := ctx.Request.FormFile(metaData.Selector())
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	bytes, err := ioutil.ReadAll(file)
+	max, hasMax := maxUploadBytes(metaData)
+	if storage := form.BlobStorage(); storage != nil {
+		bytes, err := storeThenLoadSmall(ctx, storage, header, file, metaData, max, hasMax)
+		if err != nil {
+			return err
+		}
+		b.Set(bytes)
+		return nil
+	}
+	var reader io.Reader = file
+	if hasMax {
+		reader = io.LimitReader(file, max+1)
+	}
+	bytes, err := ioutil.ReadAll(reader)
 	if err != nil {
 		return err
 	}
+	if hasMax && int64(len(bytes)) > max {
+		return ErrUploadTooLarge{MetaData: metaData, MaxUploadBytes: max}
+	}
 	b.Set(bytes)
 	return nil
 }
+
+///////////////////////////////////////////////////////////////////////////////
+// ModelBlobRefController
+
+// ModelBlobRefController handles model.BlobRef fields by streaming the
+// upload through the form's configured BlobStorage.
+type ModelBlobRefController struct{}
+
+func (self ModelBlobRefController) Supports(metaData *model.MetaData, form *Form) bool {
+	_, ok := metaData.Value.Addr().Interface().(*model.BlobRef)
+	return ok
+}
+
+func (self ModelBlobRefController) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
+	input = &FileInput{
+		Class:    form.FieldInputClass(metaData),
+		Name:     metaData.Selector(),
+		Disabled: form.IsFieldDisabled(metaData),
+	}
+	if withLabel {
+		return AddStandardLabel(form, input, metaData), nil
+	}
+	return input, nil
+}
+
+func (self ModelBlobRefController) SetValue(ctx *Context, metaData *model.MetaData, form *Form) error {
+	ref := metaData.Value.Addr().Interface().(*model.BlobRef)
+	file, header, err := ctx.Request.FormFile(metaData.Selector())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if max, ok := maxUploadBytes(metaData); ok {
+		reader = io.LimitReader(file, max+1)
+	}
+
+	storage := form.BlobStorage()
+	if storage == nil {
+		return ErrNoBlobStorageConfigured{metaData}
+	}
+	contentType := header.Header.Get("Content-Type")
+	blobRef, size, err := storage.Store(ctx, header.Filename, contentType, reader)
+	if err != nil {
+		return err
+	}
+	if max, ok := maxUploadBytes(metaData); ok && size > max {
+		storage.Delete(blobRef)
+		return ErrUploadTooLarge{MetaData: metaData, MaxUploadBytes: max}
+	}
+
+	ref.Ref = blobRef
+	ref.Name = header.Filename
+	ref.ContentType = contentType
+	ref.Size = size
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// ErrNoBlobStorageConfigured
+
+type ErrNoBlobStorageConfigured struct {
+	*model.MetaData
+}
+
+func (self ErrNoBlobStorageConfigured) Error() string {
+	return fmt.Sprintf("No BlobStorage configured for form field %s", self.Selector())
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// ErrUploadTooLarge
+
+type ErrUploadTooLarge struct {
+	*model.MetaData
+	MaxUploadBytes int64
+}
+
+func (self ErrUploadTooLarge) Error() string {
+	return fmt.Sprintf("Upload for form field %s exceeds the maximum of %d bytes", self.Selector(), self.MaxUploadBytes)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// StandardFormFieldControllers
+
+// StandardFormFieldControllers is the default FormFieldControllers used by
+// new forms. InterfaceFormFieldController is first so that a model value
+// implementing FormRenderable/FormParseable takes precedence over the
+// built-in controllers for the type it chooses to handle itself.
+var StandardFormFieldControllers = FormFieldControllers{
+	InterfaceFormFieldController{},
+	ModelStringController{},
+	ModelTextController{},
+	ModelUrlController{},
+	ModelEmailController{},
+	ModelPasswordController{},
+	ModelPhoneController{},
+	ModelBoolController{},
+	ModelChoiceController{},
+	ModelMultipleChoiceController{},
+	ModelDynamicChoiceController{},
+	ModelDateController{},
+	ModelDateTimeController{},
+	ModelFloatController{},
+	ModelIntController{},
+	ModelFileController{},
+	ModelBlobController{},
+	ModelBlobRefController{},
+}
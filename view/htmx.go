@@ -0,0 +1,92 @@
+package view
+
+import (
+	"html"
+
+	"github.com/ungerik/go-start/model"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// HTMX request helpers
+
+// IsHTMXRequest returns true if the request carries a "HX-Request: true" header.
+func (ctx *Context) IsHTMXRequest() bool {
+	return ctx.Request.Header.Get("HX-Request") == "true"
+}
+
+// HTMXTarget returns the "HX-Target" header, or an empty string if not set.
+func (ctx *Context) HTMXTarget() string {
+	return ctx.Request.Header.Get("HX-Target")
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// FieldContainer
+
+// FieldContainer wraps a form field input in a <div id="field-{selector}">
+// so a HTMX fragment response can retarget just that field.
+type FieldContainer struct {
+	ViewBaseWithId
+	Selector     string
+	Content      View
+	LiveValidate bool
+	PostURL      string
+}
+
+func (self *FieldContainer) IterateChildren(callback IterateChildrenCallback) {
+	if self.Content != nil {
+		callback(self, self.Content)
+	}
+}
+
+func (self *FieldContainer) containerId() string {
+	return "field-" + self.Selector
+}
+
+func (self *FieldContainer) Render(response *Response) (err error) {
+	response.XML.OpenTag("div").Attrib("id", self.containerId())
+	if self.LiveValidate {
+		response.XML.Attrib("hx-post", self.PostURL)
+		response.XML.Attrib("hx-trigger", "blur changed")
+		response.XML.Attrib("hx-target", "#"+self.containerId())
+	}
+	if self.Content != nil {
+		err = self.Content.Render(response)
+	}
+	response.XML.ForceCloseTag()
+	return err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// FieldError
+
+// FieldError pairs a FieldContainer with its field's validation error message.
+type FieldError struct {
+	Container *FieldContainer
+	Message   string
+}
+
+// RenderHTMXFieldErrors responds with HTTP 422 and a fragment of just
+// fieldErrors' containers plus their messages, retargeted via HX-Retarget/HX-Reswap.
+func RenderHTMXFieldErrors(ctx *Context, response *Response, fieldErrors []FieldError) error {
+	response.Header().Set("HX-Retarget", "this")
+	response.Header().Set("HX-Reswap", "outerHTML")
+	response.WriteHeader(422)
+	for _, fieldError := range fieldErrors {
+		span := &Span{Class: "error", Content: HTML(html.EscapeString(fieldError.Message))}
+		fragment := Views{fieldError.Container, span}
+		if err := fragment.Render(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewFieldContainer wraps input in a FieldContainer for metaData.
+func NewFieldContainer(input View, metaData *model.MetaData, form *Form) *FieldContainer {
+	return &FieldContainer{
+		Selector:     metaData.Selector(),
+		Content:      input,
+		LiveValidate: form.LiveValidate,
+		PostURL:      form.Action,
+	}
+}
@@ -0,0 +1,55 @@
+package view
+
+import (
+	"github.com/ungerik/go-start/model"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// FormRenderable, FormParseable
+
+// FormRenderable is an opt-in interface that a model value can implement to
+// provide its own form input view instead of requiring a dedicated
+// FormFieldController. This allows third party model types to plug into the
+// form system the same way database/sql lets types implement Scanner/Valuer.
+type FormRenderable interface {
+	// RenderFormInput creates a new form field input view for the value at metaData.
+	RenderFormInput(form *Form, metaData *model.MetaData, withLabel bool) (View, error)
+}
+
+// FormParseable is the write-side counterpart of FormRenderable, letting a
+// model value parse its own value out of submitted form data.
+type FormParseable interface {
+	// ParseFormValue sets the value at metaData from HTTP POST form data.
+	ParseFormValue(ctx *Context, metaData *model.MetaData, form *Form) error
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// InterfaceFormFieldController
+
+// InterfaceFormFieldController delegates to a model value's own
+// FormRenderable and FormParseable implementations. It is meant to sit at
+// the front of the standard FormFieldControllers slice so that user defined
+// model types take precedence over the built-in controllers for the types
+// they choose to handle themselves.
+type InterfaceFormFieldController struct{}
+
+func (self InterfaceFormFieldController) Supports(metaData *model.MetaData, form *Form) bool {
+	_, ok := metaData.Value.Addr().Interface().(FormRenderable)
+	return ok
+}
+
+func (self InterfaceFormFieldController) NewInput(withLabel bool, metaData *model.MetaData, form *Form) (input View, err error) {
+	renderable, ok := metaData.Value.Addr().Interface().(FormRenderable)
+	if !ok {
+		return nil, ErrFormFieldTypeNotSupported{metaData}
+	}
+	return renderable.RenderFormInput(form, metaData, withLabel)
+}
+
+func (self InterfaceFormFieldController) SetValue(ctx *Context, metaData *model.MetaData, form *Form) error {
+	parseable, ok := metaData.Value.Addr().Interface().(FormParseable)
+	if !ok {
+		return ErrFormFieldTypeNotSupported{metaData}
+	}
+	return parseable.ParseFormValue(ctx, metaData, form)
+}
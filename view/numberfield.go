@@ -0,0 +1,39 @@
+package view
+
+///////////////////////////////////////////////////////////////////////////////
+// NumberField
+
+// NumberField represents a HTML5 number input element so that browsers can
+// offer a native spinner and numeric keyboard on mobile devices.
+// Min, Max and Step are rendered verbatim and left empty to omit the
+// attribute, since their valid range depends on whether the bound model
+// value is an integer or a floating point number.
+type NumberField struct {
+	ViewBaseWithId
+	Class       string
+	Name        string
+	Value       string
+	Min         string
+	Max         string
+	Step        string
+	Disabled    bool
+	Placeholder string
+}
+
+func (self *NumberField) Render(response *Response) (err error) {
+	response.XML.OpenTag("input")
+	response.XML.Attrib("type", "number")
+	response.XML.Attrib("id", self.id)
+	response.XML.Attrib("name", self.Name)
+	response.XML.AttribIfNotDefault("class", self.Class)
+	response.XML.AttribIfNotDefault("value", self.Value)
+	response.XML.AttribIfNotDefault("min", self.Min)
+	response.XML.AttribIfNotDefault("max", self.Max)
+	response.XML.AttribIfNotDefault("step", self.Step)
+	response.XML.AttribIfNotDefault("placeholder", self.Placeholder)
+	if self.Disabled {
+		response.XML.Attrib("disabled", "disabled")
+	}
+	response.XML.ForceCloseTag()
+	return nil
+}
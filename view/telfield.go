@@ -0,0 +1,35 @@
+package view
+
+///////////////////////////////////////////////////////////////////////////////
+// TelField
+
+// TelField represents a HTML5 tel input element. Unlike the url and email
+// types, tel does not imply any built-in browser validation, so Pattern can
+// be set to a regular expression to validate the phone number on the client.
+type TelField struct {
+	ViewBaseWithId
+	Class       string
+	Name        string
+	Value       string
+	Pattern     string
+	Size        int
+	Disabled    bool
+	Placeholder string
+}
+
+func (self *TelField) Render(response *Response) (err error) {
+	response.XML.OpenTag("input")
+	response.XML.Attrib("type", "tel")
+	response.XML.Attrib("id", self.id)
+	response.XML.Attrib("name", self.Name)
+	response.XML.AttribIfNotDefault("class", self.Class)
+	response.XML.AttribIfNotDefault("value", self.Value)
+	response.XML.AttribIfNotDefault("pattern", self.Pattern)
+	response.XML.AttribIfNotDefault("size", self.Size)
+	response.XML.AttribIfNotDefault("placeholder", self.Placeholder)
+	if self.Disabled {
+		response.XML.Attrib("disabled", "disabled")
+	}
+	response.XML.ForceCloseTag()
+	return nil
+}
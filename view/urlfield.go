@@ -0,0 +1,32 @@
+package view
+
+///////////////////////////////////////////////////////////////////////////////
+// UrlField
+
+// UrlField represents a HTML5 url input element, which browsers validate
+// against the generic URL syntax before the form can be submitted.
+type UrlField struct {
+	ViewBaseWithId
+	Class       string
+	Name        string
+	Value       string
+	Size        int
+	Disabled    bool
+	Placeholder string
+}
+
+func (self *UrlField) Render(response *Response) (err error) {
+	response.XML.OpenTag("input")
+	response.XML.Attrib("type", "url")
+	response.XML.Attrib("id", self.id)
+	response.XML.Attrib("name", self.Name)
+	response.XML.AttribIfNotDefault("class", self.Class)
+	response.XML.AttribIfNotDefault("value", self.Value)
+	response.XML.AttribIfNotDefault("size", self.Size)
+	response.XML.AttribIfNotDefault("placeholder", self.Placeholder)
+	if self.Disabled {
+		response.XML.Attrib("disabled", "disabled")
+	}
+	response.XML.ForceCloseTag()
+	return nil
+}